@@ -5,17 +5,19 @@ import (
 	"io"
 	"io/ioutil"
 	"path"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/api/annotations"
 )
 
-const (
-	openAPISpecFileName = "spec.json"
-)
+// defaultMergeFileName is used when merge_file_name isn't set but
+// allow_merge is.
+const defaultMergeFileName = "apidocs"
 
 // Converter is everything you need to convert Micro protos into an OpenAPI spec:
 type Converter struct {
@@ -23,13 +25,59 @@ type Converter struct {
 	logger           *logrus.Logger
 	openAPISpec      *openapi3.Swagger
 	sourceInfo       *sourceCodeInfo
+
+	// messageTypes indexes every message seen across the request by its
+	// fully qualified proto name (e.g. ".acme.foo.Bar"), so field and RPC
+	// request/response types can be resolved to a schema while converting.
+	messageTypes map[string]*descriptor.DescriptorProto
+
+	// messageFiles records which proto file declared each fully qualified
+	// message name in messageTypes, so componentSchemaSources can report a
+	// schema-name collision by the files involved.
+	messageFiles map[string]string
+
+	// componentSchemaSources records which proto file first produced each
+	// entry in componentSchemas, so a later file reusing the same schema
+	// title can be reported as a collision instead of silently overwriting it.
+	componentSchemaSources map[string]string
+
+	// params holds the parsed protoc plugin parameter string.
+	params map[string]string
+
+	// externalHTTPRules holds google.api.http-shaped rules loaded from a
+	// grpc_api_configuration file, keyed by selector ("pkg.Service.Method"),
+	// used for methods that don't carry the option inline.
+	externalHTTPRules map[string]*annotations.HttpRule
+
+	// mergeSpec and mergeFileName implement allow_merge/merge_file_name:
+	// when mergeSpec is set, every input proto's paths and schemas are
+	// accumulated into one document named mergeFileName instead of one
+	// spec file per proto.
+	mergeSpec     bool
+	mergeFileName string
+
+	// openAPIConfig holds enrichment (info/servers/tags/per-selector
+	// overrides) loaded from an openapi_configuration file.
+	openAPIConfig *openAPIConfiguration
+
+	// streamingMode is the streaming parameter: sse, ndjson, both or none.
+	streamingMode string
+
+	// jsonNamesForFields is the json_names_for_fields parameter: when true
+	// (the default) schema properties and synthesized parameters use a
+	// field's json_name/lowerCamelCase form; when false, its proto name.
+	jsonNamesForFields bool
+
+	// outputFormat is the output parameter: json, yaml or both.
+	outputFormat string
 }
 
 // New returns a configured converter:
 func New(logger *logrus.Logger) *Converter {
 	return &Converter{
-		componentSchemas: make(map[string]*openapi3.Schema),
-		logger:           logger,
+		componentSchemas:       make(map[string]*openapi3.Schema),
+		componentSchemaSources: make(map[string]string),
+		logger:                 logger,
 	}
 }
 
@@ -49,6 +97,51 @@ func (c *Converter) ConvertFrom(rd io.Reader) (*plugin.CodeGeneratorResponse, er
 		return nil, err
 	}
 
+	c.params, err = parsePluginParameter(req.GetParameter())
+	if err != nil {
+		c.logger.Errorf("Invalid plugin parameter: %v", err)
+		return nil, err
+	}
+
+	if path := c.params["grpc_api_configuration"]; path != "" {
+		c.logger.Debugf("Loading external gRPC API configuration (%s)", path)
+		config, err := loadGRPCAPIConfiguration(path)
+		if err != nil {
+			c.logger.Errorf("Failed to load grpc_api_configuration (%s): %v", path, err)
+			return nil, err
+		}
+		c.externalHTTPRules = config.selectorRules()
+	}
+
+	c.mergeSpec = c.params["allow_merge"] == "true"
+	c.mergeFileName = c.params["merge_file_name"]
+	if c.mergeFileName == "" {
+		c.mergeFileName = defaultMergeFileName
+	}
+
+	if path := c.params["openapi_configuration"]; path != "" {
+		c.logger.Debugf("Loading OpenAPI enrichment configuration (%s)", path)
+		config, err := loadOpenAPIConfiguration(path)
+		if err != nil {
+			c.logger.Errorf("Failed to load openapi_configuration (%s): %v", path, err)
+			return nil, err
+		}
+		c.openAPIConfig = config
+	}
+
+	c.streamingMode = c.params["streaming"]
+	if c.streamingMode == "" {
+		c.streamingMode = defaultStreamingMode
+	}
+
+	// json_names_for_fields defaults to true.
+	c.jsonNamesForFields = c.params["json_names_for_fields"] != "false"
+
+	c.outputFormat = c.params["output"]
+	if c.outputFormat == "" {
+		c.outputFormat = defaultOutputFormat
+	}
+
 	c.openAPISpec = &openapi3.Swagger{
 		Components: openapi3.Components{
 			Schemas: make(map[string]*openapi3.SchemaRef),
@@ -68,12 +161,17 @@ func (c *Converter) ConvertFrom(rd io.Reader) (*plugin.CodeGeneratorResponse, er
 		},
 	)
 
+	if c.openAPIConfig != nil {
+		c.openAPIConfig.apply(c.openAPISpec)
+	}
+
 	c.logger.Debugf("Converting input: %v", err)
 	return c.convert(req)
 }
 
-// Converts a proto file into an OpenAPI spec:
-func (c *Converter) convertFile(file *descriptor.FileDescriptorProto) error {
+// Converts a proto file into the set of OpenAPI paths it declares, and
+// accumulates its messages into the shared component schemas:
+func (c *Converter) convertFile(file *descriptor.FileDescriptorProto) (openapi3.Paths, error) {
 
 	// Input filename:
 	protoFileName := path.Base(file.GetName())
@@ -81,7 +179,7 @@ func (c *Converter) convertFile(file *descriptor.FileDescriptorProto) error {
 	// Otherwise process MESSAGES (packages):
 	pkg, ok := c.relativelyLookupPackage(globalPkg, file.GetPackage())
 	if !ok {
-		return fmt.Errorf("no such package found: %s", file.GetPackage())
+		return nil, fmt.Errorf("no such package found: %s", file.GetPackage())
 	}
 
 	// Process messages:
@@ -92,32 +190,31 @@ func (c *Converter) convertFile(file *descriptor.FileDescriptorProto) error {
 		componentSchema, err := c.convertMessageType(pkg, msg)
 		if err != nil {
 			c.logger.Errorf("Failed to convert (%s): %v", protoFileName, err)
-			return err
+			return nil, err
+		}
+		if err := c.registerComponentSchema(componentSchema.Title, file.GetName(), componentSchema); err != nil {
+			return nil, err
 		}
-		c.componentSchemas[componentSchema.Title] = componentSchema
-		// c.openAPISpec.Components.Schemas[componentSchema.Title] = componentSchema.NewRef()
 	}
 
-	// spew.Fdump(os.Stderr, c.componentSchemas)
-
 	// Process services:
-	for _, svc := range file.GetService() {
+	filePaths := make(openapi3.Paths)
+	for svcIndex, svc := range file.GetService() {
 
 		// Convert the service:
 		c.logger.Infof("Generating service (%s) from proto file (%s)", svc.GetName(), protoFileName)
-		servicePaths, err := c.convertServiceType(file, pkg, svc)
+		servicePaths, err := c.convertServiceType(file, pkg, svc, svcIndex)
 		if err != nil {
 			c.logger.Errorf("Failed to convert (%s): %v", protoFileName, err)
-			return err
+			return nil, err
 		}
 
-		// Add the paths to our API:
 		for path, pathItem := range servicePaths {
-			c.openAPISpec.Paths[path] = pathItem
+			filePaths[path] = pathItem
 		}
 	}
 
-	return nil
+	return filePaths, nil
 }
 
 func (c *Converter) convert(req *plugin.CodeGeneratorRequest) (*plugin.CodeGeneratorResponse, error) {
@@ -130,6 +227,8 @@ func (c *Converter) convert(req *plugin.CodeGeneratorRequest) (*plugin.CodeGener
 	}
 
 	res := &plugin.CodeGeneratorResponse{}
+	pathSources := make(map[string]string)
+
 	for _, file := range req.GetProtoFile() {
 		if file.GetPackage() == "" {
 			c.logger.Warnf("Proto file (%s) doesn't specify a package", file.GetName())
@@ -138,31 +237,52 @@ func (c *Converter) convert(req *plugin.CodeGeneratorRequest) (*plugin.CodeGener
 
 		for _, msg := range file.GetMessageType() {
 			c.logger.Debugf("Loading a message (%s/%s)", file.GetPackage(), msg.GetName())
-			c.registerType(file.Package, msg)
+			c.registerType(file.Package, file.GetName(), msg)
+		}
+
+		if _, ok := generateTargets[file.GetName()]; !ok {
+			continue
+		}
+
+		c.logger.Debugf("Converting file (%s)", file.GetName())
+		filePaths, err := c.convertFile(file)
+		if err != nil {
+			res.Error = proto.String(fmt.Sprintf("Failed to convert %s: %v", file.GetName(), err))
+			return res, err
+		}
+
+		if err := checkPathCollisions(pathSources, file.GetName(), filePaths); err != nil {
+			res.Error = proto.String(err.Error())
+			return res, err
 		}
 
-		if _, ok := generateTargets[file.GetName()]; ok {
-			c.logger.Debugf("Converting file (%s)", file.GetName())
-			if err := c.convertFile(file); err != nil {
-				res.Error = proto.String(fmt.Sprintf("Failed to convert %s: %v", file.GetName(), err))
-				return res, err
+		if c.mergeSpec {
+			for path, pathItem := range filePaths {
+				c.openAPISpec.Paths[path] = pathItem
 			}
+			continue
 		}
-	}
 
-	// Marshal the OpenAPI spec:
-	marshaledSpec, err := c.openAPISpec.MarshalJSON()
-	if err != nil {
-		c.logger.Errorf("Unable to marshal the OpenAPI spec: %v", err)
-		return nil, err
+		fileSpec := c.newSwaggerFor(filePaths)
+		baseName := strings.TrimSuffix(path.Base(file.GetName()), path.Ext(file.GetName()))
+		files, err := c.specFiles(baseName, fileSpec)
+		if err != nil {
+			c.logger.Errorf("Unable to marshal the OpenAPI spec for (%s): %v", file.GetName(), err)
+			return nil, err
+		}
+		res.File = append(res.File, files...)
 	}
 
-	// Add a response file:
-	res.File = []*plugin.CodeGeneratorResponse_File{
-		{
-			Name:    proto.String(openAPISpecFileName),
-			Content: proto.String(string(marshaledSpec)),
-		},
+	if c.mergeSpec {
+		for title, schema := range c.componentSchemas {
+			c.openAPISpec.Components.Schemas[title] = schema.NewRef()
+		}
+		files, err := c.specFiles(c.mergeFileName, c.openAPISpec)
+		if err != nil {
+			c.logger.Errorf("Unable to marshal the merged OpenAPI spec: %v", err)
+			return nil, err
+		}
+		res.File = append(res.File, files...)
 	}
 
 	return res, nil
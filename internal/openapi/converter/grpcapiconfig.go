@@ -0,0 +1,89 @@
+package converter
+
+import (
+	"io/ioutil"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// grpcAPIConfiguration mirrors the well-known gRPC service config schema (the
+// same shape other grpc-gateway tooling's `grpc_api_configuration` flag
+// accepts), letting callers declare google.api.http-style bindings for
+// protos they can't edit directly.
+type grpcAPIConfiguration struct {
+	HTTP struct {
+		Rules []grpcAPIConfigRule `yaml:"rules"`
+	} `yaml:"http"`
+}
+
+// grpcAPIConfigRule is a single entry in a grpc_api_configuration file's
+// http.rules list.
+type grpcAPIConfigRule struct {
+	Selector           string              `yaml:"selector"`
+	Get                string              `yaml:"get"`
+	Put                string              `yaml:"put"`
+	Post               string              `yaml:"post"`
+	Delete             string              `yaml:"delete"`
+	Patch              string              `yaml:"patch"`
+	Body               string              `yaml:"body"`
+	ResponseBody       string              `yaml:"response_body"`
+	AdditionalBindings []grpcAPIConfigRule `yaml:"additional_bindings"`
+}
+
+// loadGRPCAPIConfiguration reads and parses a grpc_api_configuration YAML
+// file given as the plugin's `grpc_api_configuration` parameter.
+func loadGRPCAPIConfiguration(path string) (*grpcAPIConfiguration, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &grpcAPIConfiguration{}
+	if err := yaml.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// selectorRules indexes a loaded configuration's rules by selector (fully
+// qualified "pkg.Service.Method" name) for convertServiceType to consult as
+// if the methods they name carried the rule as an inline google.api.http
+// option.
+func (config *grpcAPIConfiguration) selectorRules() map[string]*annotations.HttpRule {
+	rules := make(map[string]*annotations.HttpRule, len(config.HTTP.Rules))
+	for _, rule := range config.HTTP.Rules {
+		rules[rule.Selector] = rule.httpRule()
+	}
+	return rules
+}
+
+// httpRule converts a single config rule, and recursively its
+// additional_bindings, into the annotations.HttpRule shape convertServiceType
+// already knows how to consume.
+func (r grpcAPIConfigRule) httpRule() *annotations.HttpRule {
+	rule := &annotations.HttpRule{
+		Body:         r.Body,
+		ResponseBody: r.ResponseBody,
+	}
+
+	switch {
+	case r.Get != "":
+		rule.Pattern = &annotations.HttpRule_Get{Get: r.Get}
+	case r.Put != "":
+		rule.Pattern = &annotations.HttpRule_Put{Put: r.Put}
+	case r.Post != "":
+		rule.Pattern = &annotations.HttpRule_Post{Post: r.Post}
+	case r.Delete != "":
+		rule.Pattern = &annotations.HttpRule_Delete{Delete: r.Delete}
+	case r.Patch != "":
+		rule.Pattern = &annotations.HttpRule_Patch{Patch: r.Patch}
+	}
+
+	for _, additional := range r.AdditionalBindings {
+		rule.AdditionalBindings = append(rule.AdditionalBindings, additional.httpRule())
+	}
+
+	return rule
+}
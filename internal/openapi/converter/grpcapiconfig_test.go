@@ -0,0 +1,48 @@
+package converter
+
+import "testing"
+
+func TestGRPCAPIConfigRuleHTTPRule(t *testing.T) {
+	rule := grpcAPIConfigRule{
+		Selector:     "acme.Foos.GetFoo",
+		Get:          "/v1/foos/{id}",
+		ResponseBody: "foo",
+		AdditionalBindings: []grpcAPIConfigRule{
+			{Post: "/v1/foos:batchGet", Body: "*"},
+		},
+	}
+
+	httpRule := rule.httpRule()
+
+	if got := httpRule.GetGet(); got != "/v1/foos/{id}" {
+		t.Errorf("Pattern path = %q, want %q", got, "/v1/foos/{id}")
+	}
+	if httpRule.ResponseBody != "foo" {
+		t.Errorf("ResponseBody = %q, want %q", httpRule.ResponseBody, "foo")
+	}
+	if len(httpRule.AdditionalBindings) != 1 {
+		t.Fatalf("AdditionalBindings = %v, want 1 entry", httpRule.AdditionalBindings)
+	}
+	if httpRule.AdditionalBindings[0].Body != "*" {
+		t.Errorf("AdditionalBindings[0].Body = %q, want %q", httpRule.AdditionalBindings[0].Body, "*")
+	}
+}
+
+func TestGRPCAPIConfigurationSelectorRules(t *testing.T) {
+	config := &grpcAPIConfiguration{}
+	config.HTTP.Rules = []grpcAPIConfigRule{
+		{Selector: "acme.Foos.GetFoo", Get: "/v1/foos/{id}"},
+		{Selector: "acme.Foos.DeleteFoo", Delete: "/v1/foos/{id}"},
+	}
+
+	rules := config.selectorRules()
+	if len(rules) != 2 {
+		t.Fatalf("selectorRules() = %v, want 2 entries", rules)
+	}
+	if _, ok := rules["acme.Foos.GetFoo"]; !ok {
+		t.Errorf("selectorRules() missing %q", "acme.Foos.GetFoo")
+	}
+	if _, ok := rules["acme.Foos.DeleteFoo"]; !ok {
+		t.Errorf("selectorRules() missing %q", "acme.Foos.DeleteFoo")
+	}
+}
@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+// httpBinding is a single verb+path+body mapping for an RPC method, either
+// the primary google.api.http rule or one of its additional_bindings.
+type httpBinding struct {
+	verb         string
+	path         string
+	body         string
+	responseBody string
+}
+
+// pathParamPattern matches the "{name}" and "{name=some/*/path}" placeholders
+// google.api.http paths use to mark a templated path segment.
+var pathParamPattern = regexp.MustCompile(`{([^}=]+)(=[^}]*)?}`)
+
+// httpBindingsForMethod returns every verb+path+body binding for a method,
+// in the order they should be emitted (primary rule first, then
+// additional_bindings). It prefers an inline google.api.http option; if the
+// method doesn't have one, it falls back to a rule loaded from an external
+// grpc_api_configuration file keyed by the method's selector
+// ("pkg.Service.Method"). It returns nil if neither source has a rule.
+func (c *Converter) httpBindingsForMethod(selector string, method *descriptor.MethodDescriptorProto) []httpBinding {
+	rule := extractHTTPRule(method)
+	if rule == nil {
+		rule = c.externalHTTPRules[selector]
+	}
+	if rule == nil {
+		return nil
+	}
+
+	bindings := []httpBinding{httpBindingFromRule(rule)}
+	for _, additional := range rule.GetAdditionalBindings() {
+		bindings = append(bindings, httpBindingFromRule(additional))
+	}
+
+	return bindings
+}
+
+// extractHTTPRule reads the google.api.http method option off a method, or
+// returns nil if it isn't set.
+func extractHTTPRule(method *descriptor.MethodDescriptorProto) *annotations.HttpRule {
+	if method.GetOptions() == nil {
+		return nil
+	}
+
+	ext, err := proto.GetExtension(method.GetOptions(), annotations.E_Http)
+	if err != nil {
+		return nil
+	}
+
+	rule, ok := ext.(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+
+	return rule
+}
+
+// httpBindingFromRule flattens a single HttpRule's verb/path oneof into a
+// httpBinding.
+func httpBindingFromRule(rule *annotations.HttpRule) httpBinding {
+	binding := httpBinding{body: rule.GetBody(), responseBody: rule.GetResponseBody()}
+
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		binding.verb, binding.path = "get", pattern.Get
+	case *annotations.HttpRule_Put:
+		binding.verb, binding.path = "put", pattern.Put
+	case *annotations.HttpRule_Post:
+		binding.verb, binding.path = "post", pattern.Post
+	case *annotations.HttpRule_Delete:
+		binding.verb, binding.path = "delete", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		binding.verb, binding.path = "patch", pattern.Patch
+	case *annotations.HttpRule_Custom:
+		binding.verb, binding.path = strings.ToLower(pattern.Custom.GetKind()), pattern.Custom.GetPath()
+	}
+
+	return binding
+}
+
+// openAPIPath rewrites a google.api.http path template's "{name=pattern}"
+// placeholders down to the "{name}" form OpenAPI expects, renaming each name
+// through c.fieldNameByProtoName so the path template matches the Parameter
+// convertMethod emits for that segment (e.g. under json_names_for_fields,
+// "{user_id}" becomes "{userId}", not "{user_id}").
+func (c *Converter) openAPIPath(httpPath string, inputMsg *descriptor.DescriptorProto) string {
+	return pathParamPattern.ReplaceAllStringFunc(httpPath, func(placeholder string) string {
+		name := pathParamPattern.FindStringSubmatch(placeholder)[1]
+		return "{" + c.fieldNameByProtoName(inputMsg, name) + "}"
+	})
+}
+
+// pathParamNames returns the names of every templated path parameter in a
+// google.api.http path, in the order they appear.
+func pathParamNames(httpPath string) []string {
+	matches := pathParamPattern.FindAllStringSubmatch(httpPath, -1)
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m[1]
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestHTTPBindingFromRule(t *testing.T) {
+	rule := &annotations.HttpRule{
+		Pattern:      &annotations.HttpRule_Get{Get: "/v1/foos/{id}"},
+		ResponseBody: "foo",
+	}
+
+	binding := httpBindingFromRule(rule)
+	if binding.verb != "get" {
+		t.Errorf("verb = %q, want %q", binding.verb, "get")
+	}
+	if binding.path != "/v1/foos/{id}" {
+		t.Errorf("path = %q, want %q", binding.path, "/v1/foos/{id}")
+	}
+	if binding.responseBody != "foo" {
+		t.Errorf("responseBody = %q, want %q", binding.responseBody, "foo")
+	}
+}
+
+func TestPathParamNames(t *testing.T) {
+	got := pathParamNames("/v1/{parent=publishers/*}/books/{book_id}")
+	want := []string{"parent", "book_id"}
+	if len(got) != len(want) {
+		t.Fatalf("pathParamNames() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("pathParamNames()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestConverterOpenAPIPath(t *testing.T) {
+	inputMsg := &descriptor.DescriptorProto{
+		Field: []*descriptor.FieldDescriptorProto{
+			{Name: proto.String("user_id")},
+			{Name: proto.String("note")},
+		},
+	}
+
+	t.Run("renames placeholders to match json_names_for_fields", func(t *testing.T) {
+		c := &Converter{jsonNamesForFields: true}
+		got := c.openAPIPath("/v1/users/{user_id}/notes/{note}", inputMsg)
+		want := "/v1/users/{userId}/notes/{note}"
+		if got != want {
+			t.Errorf("openAPIPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("keeps proto names when json_names_for_fields is false", func(t *testing.T) {
+		c := &Converter{jsonNamesForFields: false}
+		got := c.openAPIPath("/v1/users/{user_id}/notes/{note}", inputMsg)
+		want := "/v1/users/{user_id}/notes/{note}"
+		if got != want {
+			t.Errorf("openAPIPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("strips the = pattern suffix from a templated segment", func(t *testing.T) {
+		c := &Converter{jsonNamesForFields: true}
+		got := c.openAPIPath("/v1/{user_id=publishers/*}", inputMsg)
+		want := "/v1/{userId}"
+		if got != want {
+			t.Errorf("openAPIPath() = %q, want %q", got, want)
+		}
+	})
+}
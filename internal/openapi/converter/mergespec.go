@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// newSwaggerFor builds a standalone Swagger document carrying paths for a
+// single input proto, reusing the shared info/server/component-schema
+// settings so a non-merged spec looks exactly like the corresponding slice
+// of a merged one would.
+func (c *Converter) newSwaggerFor(paths openapi3.Paths) *openapi3.Swagger {
+	schemas := make(map[string]*openapi3.SchemaRef, len(c.componentSchemas))
+	for title, schema := range c.componentSchemas {
+		schemas[title] = schema.NewRef()
+	}
+
+	return &openapi3.Swagger{
+		Components: openapi3.Components{Schemas: schemas},
+		Info:       c.openAPISpec.Info,
+		OpenAPI:    c.openAPISpec.OpenAPI,
+		Paths:      paths,
+		Servers:    c.openAPISpec.Servers,
+		Tags:       c.openAPISpec.Tags,
+	}
+}
+
+// pathOperations returns the verb->Operation pairs set on a PathItem.
+func pathOperations(item *openapi3.PathItem) map[string]*openapi3.Operation {
+	candidates := map[string]*openapi3.Operation{
+		"GET":     item.Get,
+		"PUT":     item.Put,
+		"POST":    item.Post,
+		"DELETE":  item.Delete,
+		"PATCH":   item.Patch,
+		"OPTIONS": item.Options,
+		"HEAD":    item.Head,
+		"TRACE":   item.Trace,
+		"CONNECT": item.Connect,
+	}
+
+	ops := make(map[string]*openapi3.Operation, len(candidates))
+	for verb, op := range candidates {
+		if op != nil {
+			ops[verb] = op
+		}
+	}
+	return ops
+}
+
+// registerComponentSchema records schema as the component schema for title,
+// first produced by sourceFile, returning an error identifying both files
+// the first time a later file tries to produce a schema under a title
+// that's already taken.
+func (c *Converter) registerComponentSchema(title, sourceFile string, schema *openapi3.Schema) error {
+	if existing, ok := c.componentSchemaSources[title]; ok && existing != sourceFile {
+		return fmt.Errorf("schema collision: message (%s) is generated by both (%s) and (%s)", title, existing, sourceFile)
+	}
+	c.componentSchemaSources[title] = sourceFile
+	c.componentSchemas[title] = schema
+	return nil
+}
+
+// checkPathCollisions records which proto file first produced each
+// verb+path pair seen in sources and returns an error identifying both
+// files the first time a later file produces one that's already taken.
+func checkPathCollisions(sources map[string]string, fileName string, paths openapi3.Paths) error {
+	for p, item := range paths {
+		for verb := range pathOperations(item) {
+			key := verb + " " + p
+			if existing, ok := sources[key]; ok && existing != fileName {
+				return fmt.Errorf("path collision: %s %s is generated by both (%s) and (%s)", verb, p, existing, fileName)
+			}
+			sources[key] = fileName
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestCheckPathCollisions(t *testing.T) {
+	sources := make(map[string]string)
+
+	fooPaths := openapi3.Paths{"/v1/foos": &openapi3.PathItem{Get: openapi3.NewOperation()}}
+	if err := checkPathCollisions(sources, "acme/foo.proto", fooPaths); err != nil {
+		t.Fatalf("first file: unexpected error: %v", err)
+	}
+
+	barPaths := openapi3.Paths{"/v1/foos": &openapi3.PathItem{Get: openapi3.NewOperation()}}
+	err := checkPathCollisions(sources, "acme/bar.proto", barPaths)
+	if err == nil {
+		t.Fatal("second file reusing GET /v1/foos: expected a collision error, got none")
+	}
+	for _, want := range []string{"acme/foo.proto", "acme/bar.proto", "GET", "/v1/foos"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to mention %q", err.Error(), want)
+		}
+	}
+}
+
+func TestRegisterComponentSchema(t *testing.T) {
+	c := newTestConverter()
+	schema := openapi3.NewObjectSchema()
+
+	if err := c.registerComponentSchema("acme.Foo", "acme/foo.proto", schema); err != nil {
+		t.Fatalf("first file: unexpected error: %v", err)
+	}
+
+	err := c.registerComponentSchema("acme.Foo", "acme/bar.proto", schema)
+	if err == nil {
+		t.Fatal("second file reusing schema title acme.Foo: expected a collision error, got none")
+	}
+	for _, want := range []string{"acme/foo.proto", "acme/bar.proto", "acme.Foo"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to mention %q", err.Error(), want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+// convertMessageType converts a single proto message into an OpenAPI schema.
+// The returned schema's Title is the fully qualified message name (minus the
+// leading dot) so it can be used both as the components.schemas map key and
+// to build $ref strings pointing back at it.
+func (c *Converter) convertMessageType(pkg *pkg, msg *descriptor.DescriptorProto) (*openapi3.Schema, error) {
+	schema := openapi3.NewObjectSchema()
+	schema.Title = strings.TrimPrefix(pkg.fullyQualifiedName()+"."+msg.GetName(), ".")
+
+	for _, field := range msg.GetField() {
+		fieldRef, err := c.convertField(field)
+		if err != nil {
+			return nil, fmt.Errorf("field (%s) of message (%s): %v", field.GetName(), schema.Title, err)
+		}
+
+		schema.Properties[c.fieldName(field)] = fieldRef
+	}
+
+	return schema, nil
+}
+
+// convertField converts a single proto field into an OpenAPI schema ref,
+// wrapping it in an array schema when the field is repeated.
+func (c *Converter) convertField(field *descriptor.FieldDescriptorProto) (*openapi3.SchemaRef, error) {
+	itemRef, err := c.scalarOrRefSchema(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED && !c.isMapEntryField(field) {
+		arraySchema := openapi3.NewArraySchema()
+		arraySchema.Items = itemRef
+		return arraySchema.NewRef(), nil
+	}
+
+	return itemRef, nil
+}
+
+// scalarOrRefSchema converts a field's type into a schema ref, either an
+// inline scalar (or well-known wrapper) schema or a $ref to a message/enum
+// elsewhere in the component schemas.
+func (c *Converter) scalarOrRefSchema(field *descriptor.FieldDescriptorProto) (*openapi3.SchemaRef, error) {
+	switch field.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE, descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return openapi3.NewFloat64Schema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64, descriptor.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64:
+		return openapi3.NewInt64Schema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32, descriptor.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32:
+		return openapi3.NewInt32Schema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return openapi3.NewBoolSchema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return openapi3.NewStringSchema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return openapi3.NewBytesSchema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return openapi3.NewStringSchema().NewRef(), nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return c.schemaRefForType(field.GetTypeName())
+	default:
+		return nil, fmt.Errorf("unsupported field type: %v", field.GetType())
+	}
+}
+
+// schemaRefForType returns a $ref pointing at the component schema for a
+// fully qualified message type name, converting and registering that schema
+// first if it hasn't been seen yet.
+func (c *Converter) schemaRefForType(typeName string) (*openapi3.SchemaRef, error) {
+	msg, ok := c.lookupType(typeName)
+	if !ok {
+		return nil, fmt.Errorf("no such type: %s", typeName)
+	}
+
+	title := strings.TrimPrefix(typeName, ".")
+	if _, ok := c.componentSchemas[title]; !ok {
+		// Placeholder to break reference cycles while we convert it below.
+		c.componentSchemas[title] = openapi3.NewObjectSchema()
+
+		pkgName := strings.TrimSuffix(strings.TrimPrefix(typeName, "."), "."+msg.GetName())
+		pkg, _ := c.relativelyLookupPackage(globalPkg, pkgName)
+		converted, err := c.convertMessageType(pkg, msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.registerComponentSchema(title, c.messageFiles[typeName], converted); err != nil {
+			return nil, err
+		}
+	}
+
+	return &openapi3.SchemaRef{Ref: "#/components/schemas/" + title}, nil
+}
+
+// fieldName returns the name a proto field should appear under as an
+// OpenAPI schema property or path/query parameter. When json_names_for_fields
+// is true (the default) this is the field's json_name if set, otherwise the
+// lowerCamelCase of its proto name, matching proto3's JSON mapping; when
+// false it's the proto name verbatim.
+func (c *Converter) fieldName(field *descriptor.FieldDescriptorProto) string {
+	if !c.jsonNamesForFields {
+		return field.GetName()
+	}
+	if jsonName := field.GetJsonName(); jsonName != "" {
+		return jsonName
+	}
+	return lowerCamelCase(field.GetName())
+}
+
+// lowerCamelCase converts a proto3 snake_case field name to lowerCamelCase,
+// the same algorithm protoc uses to derive a field's default json_name.
+func lowerCamelCase(name string) string {
+	var b strings.Builder
+	capitalizeNext := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			capitalizeNext = true
+		case capitalizeNext:
+			b.WriteRune(unicode.ToUpper(r))
+			capitalizeNext = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isMapEntryField reports whether field is a proto3 map<k,v> field, which is
+// represented on the wire as a repeated synthetic MapEntry message but
+// should be rendered as a plain OpenAPI object schema rather than an array.
+func (c *Converter) isMapEntryField(field *descriptor.FieldDescriptorProto) bool {
+	if field.GetType() != descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+		return false
+	}
+	msg, ok := c.lookupType(field.GetTypeName())
+	return ok && msg.GetOptions().GetMapEntry()
+}
@@ -0,0 +1,174 @@
+package converter
+
+import (
+	"io/ioutil"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// openAPIConfiguration is the sidecar file format loaded from the
+// openapi_configuration plugin parameter. It carries everything about the
+// generated spec that can't be derived from the .proto files themselves.
+type openAPIConfiguration struct {
+	Info      *openAPIInfoConfig                  `yaml:"info"`
+	Servers   []openAPIServerConfig               `yaml:"servers"`
+	Tags      []openAPITagConfig                  `yaml:"tags"`
+	Overrides map[string]openAPIOperationOverride `yaml:"overrides"`
+}
+
+type openAPIInfoConfig struct {
+	Title       string                `yaml:"title"`
+	Description string                `yaml:"description"`
+	Version     string                `yaml:"version"`
+	Contact     *openAPIContactConfig `yaml:"contact"`
+	License     *openAPILicenseConfig `yaml:"license"`
+}
+
+type openAPIContactConfig struct {
+	Name  string `yaml:"name"`
+	URL   string `yaml:"url"`
+	Email string `yaml:"email"`
+}
+
+type openAPILicenseConfig struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+type openAPIServerConfig struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+type openAPIExternalDocsConfig struct {
+	Description string `yaml:"description"`
+	URL         string `yaml:"url"`
+}
+
+type openAPITagConfig struct {
+	Name         string                     `yaml:"name"`
+	Description  string                     `yaml:"description"`
+	ExternalDocs *openAPIExternalDocsConfig `yaml:"external_docs"`
+}
+
+// openAPIOperationOverride is keyed by selector ("pkg.Service" or
+// "pkg.Service.Method") in openAPIConfiguration.Overrides, and lets a
+// selector attach tags, a summary/description, docs, or security
+// requirements to every Operation convertServiceType builds for it.
+type openAPIOperationOverride struct {
+	Tags         []string                   `yaml:"tags"`
+	Summary      string                     `yaml:"summary"`
+	Description  string                     `yaml:"description"`
+	ExternalDocs *openAPIExternalDocsConfig `yaml:"external_docs"`
+	Deprecated   bool                       `yaml:"deprecated"`
+	Security     []map[string][]string      `yaml:"security"`
+}
+
+// loadOpenAPIConfiguration reads and parses an openapi_configuration YAML
+// file.
+func loadOpenAPIConfiguration(path string) (*openAPIConfiguration, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &openAPIConfiguration{}
+	if err := yaml.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// apply populates the parts of spec that an openAPIConfiguration can
+// override: info, servers and tags. Per-selector overrides are consulted
+// separately, while building each Operation.
+func (config *openAPIConfiguration) apply(spec *openapi3.Swagger) {
+	if config.Info != nil {
+		if config.Info.Title != "" {
+			spec.Info.Title = config.Info.Title
+		}
+		if config.Info.Description != "" {
+			spec.Info.Description = config.Info.Description
+		}
+		if config.Info.Version != "" {
+			spec.Info.Version = config.Info.Version
+		}
+		if contact := config.Info.Contact; contact != nil {
+			spec.Info.Contact = &openapi3.Contact{
+				Name:  contact.Name,
+				URL:   contact.URL,
+				Email: contact.Email,
+			}
+		}
+		if license := config.Info.License; license != nil {
+			spec.Info.License = &openapi3.License{Name: license.Name, URL: license.URL}
+		}
+	}
+
+	if len(config.Servers) > 0 {
+		spec.Servers = nil
+		for _, server := range config.Servers {
+			spec.AddServer(&openapi3.Server{URL: server.URL, Description: server.Description})
+		}
+	}
+
+	for _, tag := range config.Tags {
+		openAPITag := &openapi3.Tag{Name: tag.Name, Description: tag.Description}
+		if tag.ExternalDocs != nil {
+			openAPITag.ExternalDocs = &openapi3.ExternalDocs{
+				Description: tag.ExternalDocs.Description,
+				URL:         tag.ExternalDocs.URL,
+			}
+		}
+		spec.Tags = append(spec.Tags, openAPITag)
+	}
+}
+
+// operationOverride returns the override configured for methodSelector
+// ("pkg.Service.Method"), falling back to one configured for
+// serviceSelector ("pkg.Service"), or false if neither has one.
+func (c *Converter) operationOverride(serviceSelector, methodSelector string) (openAPIOperationOverride, bool) {
+	if c.openAPIConfig == nil {
+		return openAPIOperationOverride{}, false
+	}
+	if override, ok := c.openAPIConfig.Overrides[methodSelector]; ok {
+		return override, true
+	}
+	if override, ok := c.openAPIConfig.Overrides[serviceSelector]; ok {
+		return override, true
+	}
+	return openAPIOperationOverride{}, false
+}
+
+// apply attaches an override's tags, summary/description, docs, deprecated
+// flag and security requirements to op, replacing whatever convertMethod set
+// from the .proto itself.
+func (override openAPIOperationOverride) apply(op *openapi3.Operation) {
+	if len(override.Tags) > 0 {
+		op.Tags = override.Tags
+	}
+	if override.Summary != "" {
+		op.Summary = override.Summary
+	}
+	if override.Description != "" {
+		op.Description = override.Description
+	}
+	if override.ExternalDocs != nil {
+		op.ExternalDocs = &openapi3.ExternalDocs{
+			Description: override.ExternalDocs.Description,
+			URL:         override.ExternalDocs.URL,
+		}
+	}
+	if override.Deprecated {
+		op.Deprecated = true
+	}
+	if len(override.Security) > 0 && op.Security == nil {
+		op.Security = openapi3.NewSecurityRequirements()
+	}
+	for _, requirement := range override.Security {
+		secReq := openapi3.SecurityRequirement(requirement)
+		op.Security = op.Security.With(secReq)
+	}
+}
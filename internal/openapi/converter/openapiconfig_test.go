@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestOpenAPIConfigurationApply(t *testing.T) {
+	spec := &openapi3.Swagger{
+		Info: &openapi3.Info{Title: "Micro API", Version: "1"},
+	}
+	spec.AddServer(&openapi3.Server{URL: "https://cruft.micro.com"})
+
+	config := &openAPIConfiguration{
+		Info:    &openAPIInfoConfig{Title: "Acme API", Version: "2"},
+		Servers: []openAPIServerConfig{{URL: "https://api.acme.com", Description: "prod"}},
+		Tags:    []openAPITagConfig{{Name: "Foos", Description: "Foo operations"}},
+	}
+
+	config.apply(spec)
+
+	if spec.Info.Title != "Acme API" {
+		t.Errorf("Info.Title = %q, want %q", spec.Info.Title, "Acme API")
+	}
+	if spec.Info.Version != "2" {
+		t.Errorf("Info.Version = %q, want %q", spec.Info.Version, "2")
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != "https://api.acme.com" {
+		t.Errorf("Servers = %v, want a single server at %q", spec.Servers, "https://api.acme.com")
+	}
+	if len(spec.Tags) != 1 || spec.Tags[0].Name != "Foos" {
+		t.Errorf("Tags = %v, want a single tag named %q", spec.Tags, "Foos")
+	}
+}
+
+func TestOpenAPIConfigurationApplyLeavesUnsetFieldsAlone(t *testing.T) {
+	spec := &openapi3.Swagger{Info: &openapi3.Info{Title: "Micro API", Version: "1"}}
+	config := &openAPIConfiguration{Info: &openAPIInfoConfig{Description: "Only the description is set"}}
+
+	config.apply(spec)
+
+	if spec.Info.Title != "Micro API" {
+		t.Errorf("Info.Title = %q, want it left as %q", spec.Info.Title, "Micro API")
+	}
+	if spec.Info.Description != "Only the description is set" {
+		t.Errorf("Info.Description = %q, want %q", spec.Info.Description, "Only the description is set")
+	}
+}
+
+func TestOperationOverrideSelectorPrecedence(t *testing.T) {
+	c := &Converter{
+		openAPIConfig: &openAPIConfiguration{
+			Overrides: map[string]openAPIOperationOverride{
+				"acme.Foos":        {Summary: "service-level override"},
+				"acme.Foos.GetFoo": {Summary: "method-level override"},
+			},
+		},
+	}
+
+	override, ok := c.operationOverride("acme.Foos", "acme.Foos.GetFoo")
+	if !ok {
+		t.Fatal("operationOverride: expected an override, got none")
+	}
+	if override.Summary != "method-level override" {
+		t.Errorf("Summary = %q, want the method-level override to win over the service-level one", override.Summary)
+	}
+
+	override, ok = c.operationOverride("acme.Foos", "acme.Foos.DeleteFoo")
+	if !ok {
+		t.Fatal("operationOverride: expected the service-level override as a fallback, got none")
+	}
+	if override.Summary != "service-level override" {
+		t.Errorf("Summary = %q, want the service-level override", override.Summary)
+	}
+
+	if _, ok := c.operationOverride("acme.Bars", "acme.Bars.GetBar"); ok {
+		t.Error("operationOverride: expected no override for an unconfigured selector")
+	}
+}
+
+func TestOperationOverrideApply(t *testing.T) {
+	op := openapi3.NewOperation()
+	op.Tags = []string{"Foos"}
+
+	override := openAPIOperationOverride{
+		Tags:       []string{"Custom"},
+		Summary:    "Get a foo",
+		Deprecated: true,
+	}
+	override.apply(op)
+
+	if len(op.Tags) != 1 || op.Tags[0] != "Custom" {
+		t.Errorf("Tags = %v, want [Custom]", op.Tags)
+	}
+	if op.Summary != "Get a foo" {
+		t.Errorf("Summary = %q, want %q", op.Summary, "Get a foo")
+	}
+	if !op.Deprecated {
+		t.Error("Deprecated = false, want true")
+	}
+}
@@ -0,0 +1,66 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/protobuf/proto"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Values accepted by the output plugin parameter.
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+	outputBoth = "both"
+)
+
+// defaultOutputFormat is used when the output parameter isn't set.
+const defaultOutputFormat = outputJSON
+
+// specFiles marshals spec as baseName.json and/or baseName.yaml per the
+// configured output format.
+func (c *Converter) specFiles(baseName string, spec *openapi3.Swagger) ([]*plugin.CodeGeneratorResponse_File, error) {
+	var files []*plugin.CodeGeneratorResponse_File
+
+	if c.outputFormat == outputJSON || c.outputFormat == outputBoth {
+		marshaled, err := spec.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &plugin.CodeGeneratorResponse_File{
+			Name:    proto.String(baseName + ".json"),
+			Content: proto.String(string(marshaled)),
+		})
+	}
+
+	if c.outputFormat == outputYAML || c.outputFormat == outputBoth {
+		marshaled, err := marshalYAML(spec)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &plugin.CodeGeneratorResponse_File{
+			Name:    proto.String(baseName + ".yaml"),
+			Content: proto.String(string(marshaled)),
+		})
+	}
+
+	return files, nil
+}
+
+// marshalYAML renders a Swagger document as YAML by round-tripping through
+// its JSON marshaling, since openapi3.Swagger's struct tags are JSON-only.
+func marshalYAML(spec *openapi3.Swagger) ([]byte, error) {
+	marshaled, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(marshaled, &generic); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(generic)
+}
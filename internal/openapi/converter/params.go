@@ -0,0 +1,66 @@
+package converter
+
+import "fmt"
+
+// knownPluginParameters are the parameter keys this converter understands;
+// parsePluginParameter rejects anything else so a typo'd parameter fails
+// loudly instead of silently doing nothing.
+var knownPluginParameters = map[string]bool{
+	"grpc_api_configuration": true,
+	"openapi_configuration":  true,
+	"allow_merge":            true,
+	"merge_file_name":        true,
+	"streaming":              true,
+	"json_names_for_fields":  true,
+	"output":                 true,
+}
+
+// parsePluginParameter splits protoc's comma-separated "key=value,key=value"
+// plugin parameter string into a map. It supports double-quoted values that
+// may themselves contain commas (e.g. `key="a,b"`) and repeated keys, where
+// the last occurrence wins.
+func parsePluginParameter(parameter string) (map[string]string, error) {
+	params := make(map[string]string)
+
+	var key, value []byte
+	inQuotes, inValue := false, false
+
+	flush := func() error {
+		defer func() {
+			key, value = nil, nil
+			inValue = false
+		}()
+
+		if len(key) == 0 {
+			return nil
+		}
+		if !knownPluginParameters[string(key)] {
+			return fmt.Errorf("unknown plugin parameter: %s", key)
+		}
+		params[string(key)] = string(value)
+		return nil
+	}
+
+	for i := 0; i < len(parameter); i++ {
+		switch ch := parameter[i]; {
+		case ch == '"':
+			inQuotes = !inQuotes
+		case ch == '=' && !inValue && !inQuotes:
+			inValue = true
+		case ch == ',' && !inQuotes:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case inValue:
+			value = append(value, ch)
+		default:
+			key = append(key, ch)
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
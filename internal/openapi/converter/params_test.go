@@ -0,0 +1,66 @@
+package converter
+
+import "testing"
+
+func TestParsePluginParameter(t *testing.T) {
+	cases := []struct {
+		name      string
+		parameter string
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "empty",
+			parameter: "",
+			want:      map[string]string{},
+		},
+		{
+			name:      "single pair",
+			parameter: "output=yaml",
+			want:      map[string]string{"output": "yaml"},
+		},
+		{
+			name:      "multiple pairs",
+			parameter: "output=yaml,allow_merge=true",
+			want:      map[string]string{"output": "yaml", "allow_merge": "true"},
+		},
+		{
+			name:      "quoted value containing a comma",
+			parameter: `merge_file_name="apidocs,v1"`,
+			want:      map[string]string{"merge_file_name": "apidocs,v1"},
+		},
+		{
+			name:      "repeated key, last wins",
+			parameter: "output=json,output=yaml",
+			want:      map[string]string{"output": "yaml"},
+		},
+		{
+			name:      "unknown key",
+			parameter: "bogus=true",
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parsePluginParameter(tc.parameter)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parsePluginParameter(%q): expected an error, got none", tc.parameter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePluginParameter(%q): unexpected error: %v", tc.parameter, err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("parsePluginParameter(%q) = %v, want %v", tc.parameter, got, tc.want)
+			}
+			for k, v := range tc.want {
+				if got[k] != v {
+					t.Errorf("parsePluginParameter(%q)[%q] = %q, want %q", tc.parameter, k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package converter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+// convertServiceType converts a single proto service into the set of
+// OpenAPI paths it exposes. Methods that carry a google.api.http option use
+// its verb/path/body mapping; methods without one fall back to a synthetic
+// "/pkg.Service/Method" POST endpoint so every RPC is still reachable.
+func (c *Converter) convertServiceType(file *descriptor.FileDescriptorProto, pkg *pkg, svc *descriptor.ServiceDescriptorProto, svcIndex int) (openapi3.Paths, error) {
+	paths := make(openapi3.Paths)
+
+	serviceName := strings.TrimPrefix(pkg.fullyQualifiedName()+"."+svc.GetName(), ".")
+
+	for methodIndex, method := range svc.GetMethod() {
+		selector := serviceName + "." + method.GetName()
+		bindings := c.httpBindingsForMethod(selector, method)
+		if len(bindings) == 0 {
+			bindings = []httpBinding{defaultHTTPBinding(pkg, svc, method)}
+		}
+
+		inputMsg, ok := c.lookupType(method.GetInputType())
+		if !ok {
+			return nil, fmt.Errorf("method (%s.%s): no such input type: %s", svc.GetName(), method.GetName(), method.GetInputType())
+		}
+
+		for _, binding := range bindings {
+			op, err := c.convertMethod(file, svc, method, binding, serviceName, selector, svcIndex, methodIndex)
+			if err != nil {
+				return nil, fmt.Errorf("method (%s.%s): %v", svc.GetName(), method.GetName(), err)
+			}
+
+			path := c.openAPIPath(binding.path, inputMsg)
+			pathItem := paths[path]
+			if pathItem == nil {
+				pathItem = &openapi3.PathItem{}
+				paths[path] = pathItem
+			}
+			if err := setPathOperation(pathItem, strings.ToUpper(binding.verb), op); err != nil {
+				return nil, fmt.Errorf("method (%s.%s): %v", svc.GetName(), method.GetName(), err)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// setPathOperation assigns op to item under verb, returning an error instead
+// of panicking when verb isn't one of the nine methods openapi3.PathItem has
+// a field for. PathItem.SetOperation panics on anything else, which a
+// custom { kind: "..." } binding can easily trigger (e.g. "LIST" isn't an
+// HTTP method), and a single unsupported binding shouldn't crash the whole
+// protoc run.
+func setPathOperation(item *openapi3.PathItem, verb string, op *openapi3.Operation) error {
+	switch verb {
+	case http.MethodConnect:
+		item.Connect = op
+	case http.MethodDelete:
+		item.Delete = op
+	case http.MethodGet:
+		item.Get = op
+	case http.MethodHead:
+		item.Head = op
+	case http.MethodOptions:
+		item.Options = op
+	case http.MethodPatch:
+		item.Patch = op
+	case http.MethodPost:
+		item.Post = op
+	case http.MethodPut:
+		item.Put = op
+	case http.MethodTrace:
+		item.Trace = op
+	default:
+		return fmt.Errorf("custom binding kind %q doesn't map to a supported HTTP method", verb)
+	}
+	return nil
+}
+
+// defaultHTTPBinding synthesizes a verb+path for methods that don't declare
+// a google.api.http option, so every RPC is still exposed somewhere.
+func defaultHTTPBinding(pkg *pkg, svc *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) httpBinding {
+	return httpBinding{
+		verb: "post",
+		path: fmt.Sprintf("/%s/%s", strings.TrimPrefix(pkg.fullyQualifiedName()+"."+svc.GetName(), "."), method.GetName()),
+		body: "*",
+	}
+}
+
+// convertMethod builds the OpenAPI Operation for a single verb+path binding
+// of a method, applying the body-mapping rules: body:"*" sends the whole
+// input as the request body, body:"<field>" sends just that sub-message,
+// and no body turns the input's remaining fields into query parameters.
+// Any openapi_configuration override for serviceSelector/methodSelector is
+// applied on top; a method's leading proto comment is only used as a
+// description fallback when no override supplies one.
+func (c *Converter) convertMethod(file *descriptor.FileDescriptorProto, svc *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto, binding httpBinding, serviceSelector, methodSelector string, svcIndex, methodIndex int) (*openapi3.Operation, error) {
+	op := openapi3.NewOperation()
+	op.OperationID = svc.GetName() + "_" + method.GetName()
+	op.Tags = []string{svc.GetName()}
+
+	if comment := c.sourceInfo.leadingComments(file.GetName(), fileDescriptorServiceTag, int32(svcIndex), serviceDescriptorMethodTag, int32(methodIndex)); comment != "" {
+		op.Description = comment
+	}
+
+	if override, ok := c.operationOverride(serviceSelector, methodSelector); ok {
+		override.apply(op)
+	}
+
+	inputMsg, ok := c.lookupType(method.GetInputType())
+	if !ok {
+		return nil, fmt.Errorf("no such input type: %s", method.GetInputType())
+	}
+
+	pathParams := make(map[string]bool)
+	for _, name := range pathParamNames(binding.path) {
+		pathParams[name] = true
+		op.AddParameter(&openapi3.Parameter{
+			Name:     c.fieldNameByProtoName(inputMsg, name),
+			In:       "path",
+			Required: true,
+			Schema:   openapi3.NewStringSchema().NewRef(),
+		})
+	}
+
+	switch {
+	case binding.body == "*":
+		bodySchema, err := c.schemaRefForType(method.GetInputType())
+		if err != nil {
+			return nil, err
+		}
+		op.RequestBody = requestBodyFromSchema(bodySchema)
+
+	case binding.body != "":
+		bodyField := fieldByName(inputMsg, binding.body)
+		if bodyField == nil {
+			return nil, fmt.Errorf("body field (%s) not found on (%s)", binding.body, method.GetInputType())
+		}
+		bodySchema, err := c.scalarOrRefSchema(bodyField)
+		if err != nil {
+			return nil, err
+		}
+		op.RequestBody = requestBodyFromSchema(bodySchema)
+
+	default:
+		for _, field := range inputMsg.GetField() {
+			if pathParams[field.GetName()] {
+				continue
+			}
+			if t := field.GetType(); t == descriptor.FieldDescriptorProto_TYPE_MESSAGE || t == descriptor.FieldDescriptorProto_TYPE_GROUP {
+				// Message-typed query parameters aren't representable as a
+				// single query string value; skip rather than fail the
+				// whole method.
+				c.logger.Warnf("Skipping non-scalar query parameter (%s) on (%s.%s)", field.GetName(), svc.GetName(), method.GetName())
+				continue
+			}
+			schema, err := c.scalarOrRefSchema(field)
+			if err != nil {
+				return nil, err
+			}
+			op.AddParameter(&openapi3.Parameter{
+				Name:   c.fieldName(field),
+				In:     "query",
+				Schema: schema,
+			})
+		}
+	}
+
+	c.applyStreamingRequest(op, method)
+
+	responseSchema, err := c.responseSchema(method, binding.responseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	response := openapi3.NewResponse().WithDescription("OK")
+	response.Content = c.streamingResponseContent(method, responseSchema)
+	op.AddResponse(200, response)
+
+	applyStreamingExtension(op, method)
+
+	return op, nil
+}
+
+// responseSchema returns the schema for a method's response, narrowed down
+// to responseBodyField when response_body names one.
+func (c *Converter) responseSchema(method *descriptor.MethodDescriptorProto, responseBodyField string) (*openapi3.SchemaRef, error) {
+	if responseBodyField == "" {
+		return c.schemaRefForType(method.GetOutputType())
+	}
+
+	outputMsg, ok := c.lookupType(method.GetOutputType())
+	if !ok {
+		return nil, fmt.Errorf("no such output type: %s", method.GetOutputType())
+	}
+
+	field := fieldByName(outputMsg, responseBodyField)
+	if field == nil {
+		return nil, fmt.Errorf("response_body field (%s) not found on (%s)", responseBodyField, method.GetOutputType())
+	}
+
+	return c.scalarOrRefSchema(field)
+}
+
+// requestBodyFromSchema wraps a schema as a JSON request body ref.
+func requestBodyFromSchema(schema *openapi3.SchemaRef) *openapi3.RequestBodyRef {
+	return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchemaRef(schema)}
+}
+
+// fieldByName finds a direct field of msg by its proto (snake_case) name.
+func fieldByName(msg *descriptor.DescriptorProto, name string) *descriptor.FieldDescriptorProto {
+	for _, field := range msg.GetField() {
+		if field.GetName() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// fieldNameByProtoName renders the field named protoName on msg the same
+// way convertMessageType would, falling back to protoName verbatim if msg
+// doesn't declare such a field (e.g. it comes from a parent message).
+func (c *Converter) fieldNameByProtoName(msg *descriptor.DescriptorProto, protoName string) string {
+	if field := fieldByName(msg, protoName); field != nil {
+		return c.fieldName(field)
+	}
+	return protoName
+}
@@ -0,0 +1,266 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+// newTestConverter returns a Converter wired up enough to run
+// convertServiceType against hand-built descriptors.
+func newTestConverter() *Converter {
+	return &Converter{
+		componentSchemas:       make(map[string]*openapi3.Schema),
+		componentSchemaSources: make(map[string]string),
+		messageTypes:           make(map[string]*descriptor.DescriptorProto),
+		messageFiles:           make(map[string]string),
+		logger:                 logrus.New(),
+		sourceInfo:             newSourceCodeInfo(nil),
+		jsonNamesForFields:     true,
+		streamingMode:          streamingBoth,
+	}
+}
+
+func withHTTPGet(method *descriptor.MethodDescriptorProto, path string) *descriptor.MethodDescriptorProto {
+	rule := &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: path}}
+	options := &descriptor.MethodOptions{}
+	if err := proto.SetExtension(options, annotations.E_Http, rule); err != nil {
+		panic(err)
+	}
+	method.Options = options
+	return method
+}
+
+// TestConvertServiceTypePathParamNamingMatchesParameter guards against the
+// path template and its Parameter falling out of sync: the emitted path key
+// must use the same name as the path Parameter convertMethod attaches to its
+// operation, or the spec is invalid and unusable by client generators.
+func TestConvertServiceTypePathParamNamingMatchesParameter(t *testing.T) {
+	inputMsg := &descriptor.DescriptorProto{
+		Name: proto.String("GetFooRequest"),
+		Field: []*descriptor.FieldDescriptorProto{
+			{Name: proto.String("user_id"), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum()},
+			{Name: proto.String("note"), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum()},
+		},
+	}
+	outputMsg := &descriptor.DescriptorProto{
+		Name: proto.String("Foo"),
+	}
+
+	method := withHTTPGet(&descriptor.MethodDescriptorProto{
+		Name:       proto.String("GetFoo"),
+		InputType:  proto.String(".acme.GetFooRequest"),
+		OutputType: proto.String(".acme.Foo"),
+	}, "/v1/users/{user_id}")
+
+	svc := &descriptor.ServiceDescriptorProto{
+		Name:   proto.String("Foos"),
+		Method: []*descriptor.MethodDescriptorProto{method},
+	}
+
+	file := &descriptor.FileDescriptorProto{
+		Name:    proto.String("acme/foo.proto"),
+		Package: proto.String("acme"),
+	}
+
+	c := newTestConverter()
+	c.registerType(proto.String("acme"), file.GetName(), inputMsg)
+	c.registerType(proto.String("acme"), file.GetName(), outputMsg)
+
+	pkg, _ := c.relativelyLookupPackage(globalPkg, "acme")
+	paths, err := c.convertServiceType(file, pkg, svc, 0)
+	if err != nil {
+		t.Fatalf("convertServiceType() error = %v", err)
+	}
+
+	const wantPath = "/v1/users/{userId}"
+	pathItem, ok := paths[wantPath]
+	if !ok {
+		keys := make([]string, 0, len(paths))
+		for k := range paths {
+			keys = append(keys, k)
+		}
+		t.Fatalf("paths = %v, missing key %q (path placeholder wasn't renamed to match json_names_for_fields)", keys, wantPath)
+	}
+
+	op := pathItem.Get
+	if op == nil {
+		t.Fatalf("expected a GET operation on %q", wantPath)
+	}
+
+	var found bool
+	for _, param := range op.Parameters {
+		if param.Value != nil && param.Value.Name == "userId" && param.Value.In == "path" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("operation has no path parameter named %q to match the %q placeholder", "userId", wantPath)
+	}
+}
+
+// TestSetPathOperation covers every verb openapi3.PathItem has a field for,
+// plus a custom { kind: "..." } verb that doesn't map to any of them.
+func TestSetPathOperation(t *testing.T) {
+	op := openapi3.NewOperation()
+
+	for verb, get := range map[string]func(*openapi3.PathItem) *openapi3.Operation{
+		"GET":     func(p *openapi3.PathItem) *openapi3.Operation { return p.Get },
+		"PUT":     func(p *openapi3.PathItem) *openapi3.Operation { return p.Put },
+		"POST":    func(p *openapi3.PathItem) *openapi3.Operation { return p.Post },
+		"DELETE":  func(p *openapi3.PathItem) *openapi3.Operation { return p.Delete },
+		"PATCH":   func(p *openapi3.PathItem) *openapi3.Operation { return p.Patch },
+		"HEAD":    func(p *openapi3.PathItem) *openapi3.Operation { return p.Head },
+		"OPTIONS": func(p *openapi3.PathItem) *openapi3.Operation { return p.Options },
+		"TRACE":   func(p *openapi3.PathItem) *openapi3.Operation { return p.Trace },
+		"CONNECT": func(p *openapi3.PathItem) *openapi3.Operation { return p.Connect },
+	} {
+		item := &openapi3.PathItem{}
+		if err := setPathOperation(item, verb, op); err != nil {
+			t.Errorf("setPathOperation(%q): unexpected error: %v", verb, err)
+		}
+		if get(item) != op {
+			t.Errorf("setPathOperation(%q) didn't set the expected field", verb)
+		}
+	}
+
+	item := &openapi3.PathItem{}
+	if err := setPathOperation(item, "LIST", op); err == nil {
+		t.Error(`setPathOperation("LIST"): expected an error, got none`)
+	}
+}
+
+// TestConvertServiceTypeRejectsUnsupportedCustomVerb guards against a
+// custom { kind: "..." } binding whose kind isn't one of the nine HTTP
+// methods openapi3.PathItem supports: it must surface as an error, not
+// panic through PathItem.SetOperation and crash the whole protoc run.
+func TestConvertServiceTypeRejectsUnsupportedCustomVerb(t *testing.T) {
+	inputMsg := &descriptor.DescriptorProto{Name: proto.String("ListFoosRequest")}
+	outputMsg := &descriptor.DescriptorProto{Name: proto.String("ListFoosResponse")}
+
+	rule := &annotations.HttpRule{Pattern: &annotations.HttpRule_Custom{
+		Custom: &annotations.CustomHttpPattern{Kind: "LIST", Path: "/v1/foos"},
+	}}
+	options := &descriptor.MethodOptions{}
+	if err := proto.SetExtension(options, annotations.E_Http, rule); err != nil {
+		t.Fatal(err)
+	}
+
+	method := &descriptor.MethodDescriptorProto{
+		Name:       proto.String("ListFoos"),
+		InputType:  proto.String(".acme.ListFoosRequest"),
+		OutputType: proto.String(".acme.ListFoosResponse"),
+		Options:    options,
+	}
+	svc := &descriptor.ServiceDescriptorProto{Name: proto.String("Foos"), Method: []*descriptor.MethodDescriptorProto{method}}
+	file := &descriptor.FileDescriptorProto{Name: proto.String("acme/foo.proto"), Package: proto.String("acme")}
+
+	c := newTestConverter()
+	c.registerType(proto.String("acme"), file.GetName(), inputMsg)
+	c.registerType(proto.String("acme"), file.GetName(), outputMsg)
+
+	pkg, _ := c.relativelyLookupPackage(globalPkg, "acme")
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("convertServiceType panicked on an unsupported custom verb: %v", r)
+		}
+	}()
+
+	if _, err := c.convertServiceType(file, pkg, svc, 0); err == nil {
+		t.Error("convertServiceType: expected an error for an unsupported custom verb, got none")
+	}
+}
+
+// TestConvertMethodBodyMapping covers the three body-mapping branches
+// convertMethod switches on: body:"*" (whole input), body:"<field>" (a
+// sub-message), and no body (remaining fields become query parameters).
+func TestConvertMethodBodyMapping(t *testing.T) {
+	fooType := &descriptor.DescriptorProto{
+		Name: proto.String("Foo"),
+		Field: []*descriptor.FieldDescriptorProto{
+			{Name: proto.String("name"), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum()},
+		},
+	}
+	inputMsg := &descriptor.DescriptorProto{
+		Name: proto.String("UpdateFooRequest"),
+		Field: []*descriptor.FieldDescriptorProto{
+			{Name: proto.String("id"), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum()},
+			{Name: proto.String("foo"), Type: descriptor.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".acme.Foo")},
+			{Name: proto.String("active"), Type: descriptor.FieldDescriptorProto_TYPE_BOOL.Enum()},
+		},
+	}
+
+	file := &descriptor.FileDescriptorProto{Name: proto.String("acme/foo.proto"), Package: proto.String("acme")}
+	svc := &descriptor.ServiceDescriptorProto{Name: proto.String("Foos")}
+
+	newConverterWithTypes := func() *Converter {
+		c := newTestConverter()
+		c.registerType(proto.String("acme"), file.GetName(), inputMsg)
+		c.registerType(proto.String("acme"), file.GetName(), fooType)
+		return c
+	}
+
+	t.Run(`body:"*" sends the whole input as the request body`, func(t *testing.T) {
+		c := newConverterWithTypes()
+		method := &descriptor.MethodDescriptorProto{Name: proto.String("UpdateFoo"), InputType: proto.String(".acme.UpdateFooRequest"), OutputType: proto.String(".acme.Foo")}
+		binding := httpBinding{verb: "post", path: "/v1/foos/{id}", body: "*"}
+
+		op, err := c.convertMethod(file, svc, method, binding, "acme.Foos", "acme.Foos.UpdateFoo", 0, 0)
+		if err != nil {
+			t.Fatalf("convertMethod() error = %v", err)
+		}
+		if op.RequestBody == nil || op.RequestBody.Value == nil {
+			t.Fatalf("expected a request body, got none")
+		}
+		if len(op.Parameters) != 1 {
+			t.Errorf("parameters = %v, want only the %q path parameter", op.Parameters, "id")
+		}
+	})
+
+	t.Run(`body:"foo" sends just that sub-message`, func(t *testing.T) {
+		c := newConverterWithTypes()
+		method := &descriptor.MethodDescriptorProto{Name: proto.String("UpdateFoo"), InputType: proto.String(".acme.UpdateFooRequest"), OutputType: proto.String(".acme.Foo")}
+		binding := httpBinding{verb: "patch", path: "/v1/foos/{id}", body: "foo"}
+
+		op, err := c.convertMethod(file, svc, method, binding, "acme.Foos", "acme.Foos.UpdateFoo", 0, 0)
+		if err != nil {
+			t.Fatalf("convertMethod() error = %v", err)
+		}
+		if op.RequestBody == nil || op.RequestBody.Value == nil {
+			t.Fatalf("expected a request body, got none")
+		}
+		ref := op.RequestBody.Value.Content.Get("application/json").Schema
+		if ref == nil || ref.Ref != "#/components/schemas/acme.Foo" {
+			t.Errorf("request body schema = %v, want a ref to acme.Foo", ref)
+		}
+	})
+
+	t.Run("no body turns remaining fields into query parameters", func(t *testing.T) {
+		c := newConverterWithTypes()
+		method := &descriptor.MethodDescriptorProto{Name: proto.String("UpdateFoo"), InputType: proto.String(".acme.UpdateFooRequest"), OutputType: proto.String(".acme.Foo")}
+		binding := httpBinding{verb: "get", path: "/v1/foos/{id}"}
+
+		op, err := c.convertMethod(file, svc, method, binding, "acme.Foos", "acme.Foos.UpdateFoo", 0, 0)
+		if err != nil {
+			t.Fatalf("convertMethod() error = %v", err)
+		}
+		if op.RequestBody != nil {
+			t.Errorf("expected no request body, got %v", op.RequestBody)
+		}
+
+		var gotQueryParam bool
+		for _, param := range op.Parameters {
+			if param.Value != nil && param.Value.In == "query" && param.Value.Name == "active" {
+				gotQueryParam = true
+			}
+		}
+		if !gotQueryParam {
+			t.Errorf("parameters = %v, want a query parameter named %q (the message-typed %q field should be skipped, not erroring)", op.Parameters, "active", "foo")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+// Field numbers within FileDescriptorProto that SourceCodeInfo_Location
+// paths are built from, per descriptor.proto.
+const (
+	fileDescriptorMessageTypeTag = 4
+	fileDescriptorServiceTag     = 6
+	serviceDescriptorMethodTag   = 2
+)
+
+// sourceCodeInfo indexes the comments attached to every declaration across a
+// CodeGeneratorRequest's proto files, keyed by file name and then by the
+// dotted path descriptor.SourceCodeInfo_Location uses to address a node in
+// the FileDescriptorProto tree (e.g. "6.0.2.0" for the first method of the
+// first service).
+type sourceCodeInfo struct {
+	comments map[string]map[string]*descriptor.SourceCodeInfo_Location
+}
+
+// newSourceCodeInfo builds a sourceCodeInfo index from the proto files in a
+// CodeGeneratorRequest.
+func newSourceCodeInfo(files []*descriptor.FileDescriptorProto) *sourceCodeInfo {
+	info := &sourceCodeInfo{
+		comments: make(map[string]map[string]*descriptor.SourceCodeInfo_Location),
+	}
+
+	for _, file := range files {
+		locations := make(map[string]*descriptor.SourceCodeInfo_Location)
+		for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+			locations[pathKey(loc.Path)] = loc
+		}
+		info.comments[file.GetName()] = locations
+	}
+
+	return info
+}
+
+// leadingComments returns the leading comment attached to the declaration at
+// path within fileName, with surrounding whitespace trimmed, or "" if there
+// isn't one.
+func (s *sourceCodeInfo) leadingComments(fileName string, path ...int32) string {
+	locations, ok := s.comments[fileName]
+	if !ok {
+		return ""
+	}
+
+	loc, ok := locations[pathKey(path)]
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(loc.GetLeadingComments())
+}
+
+// pathKey turns a SourceCodeInfo path into a map key.
+func pathKey(path []int32) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(int(p))
+	}
+	return strings.Join(parts, ".")
+}
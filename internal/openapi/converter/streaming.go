@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+// Values accepted by the streaming plugin parameter.
+const (
+	streamingSSE    = "sse"
+	streamingNDJSON = "ndjson"
+	streamingBoth   = "both"
+	streamingNone   = "none"
+)
+
+// defaultStreamingMode is used when the streaming parameter isn't set.
+const defaultStreamingMode = streamingBoth
+
+// streamingKind reports the x-grpc-streaming vendor extension value for a
+// method, or "" if it isn't a streaming method.
+func streamingKind(method *descriptor.MethodDescriptorProto) string {
+	switch {
+	case method.GetServerStreaming() && method.GetClientStreaming():
+		return "bidi"
+	case method.GetClientStreaming():
+		return "client"
+	case method.GetServerStreaming():
+		return "server"
+	default:
+		return ""
+	}
+}
+
+// applyStreamingRequest swaps a streaming method's request body content type
+// to application/x-ndjson, since a client-streaming call sends a sequence of
+// input messages rather than a single JSON document. Only applies when the
+// configured streaming mode actually produces ndjson content, matching
+// streamingResponseContent's per-content-type gating.
+func (c *Converter) applyStreamingRequest(op *openapi3.Operation, method *descriptor.MethodDescriptorProto) {
+	if !method.GetClientStreaming() {
+		return
+	}
+	if c.streamingMode != streamingNDJSON && c.streamingMode != streamingBoth {
+		return
+	}
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return
+	}
+
+	schema := op.RequestBody.Value.Content.Get("application/json").Schema
+	op.RequestBody.Value.Content = openapi3.NewContentWithSchemaRef(schema, []string{"application/x-ndjson"})
+}
+
+// streamingResponseContent builds the response content map for a method,
+// adding text/event-stream and/or application/x-ndjson representations of
+// responseSchema alongside the plain JSON one when the
+// method is server-streaming, per the configured streaming mode.
+func (c *Converter) streamingResponseContent(method *descriptor.MethodDescriptorProto, responseSchema *openapi3.SchemaRef) openapi3.Content {
+	content := openapi3.NewContentWithJSONSchemaRef(responseSchema)
+
+	if c.streamingMode == streamingNone || !method.GetServerStreaming() {
+		return content
+	}
+
+	if c.streamingMode == streamingSSE || c.streamingMode == streamingBoth {
+		content["text/event-stream"] = openapi3.NewMediaType().WithSchemaRef(responseSchema)
+	}
+
+	if c.streamingMode == streamingNDJSON || c.streamingMode == streamingBoth {
+		streamSchema := openapi3.NewArraySchema()
+		streamSchema.Items = responseSchema
+		content["application/x-ndjson"] = openapi3.NewMediaType().WithSchemaRef(streamSchema.NewRef())
+	}
+
+	return content
+}
+
+// applyStreamingExtension tags a streaming Operation with the
+// x-grpc-streaming vendor extension so downstream tooling can recognize it
+// without inspecting content types.
+func applyStreamingExtension(op *openapi3.Operation, method *descriptor.MethodDescriptorProto) {
+	kind := streamingKind(method)
+	if kind == "" {
+		return
+	}
+
+	if op.ExtensionProps.Extensions == nil {
+		op.ExtensionProps.Extensions = make(map[string]interface{})
+	}
+	op.ExtensionProps.Extensions["x-grpc-streaming"] = kind
+}
@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+func clientStreamingMethod() *descriptor.MethodDescriptorProto {
+	t := true
+	return &descriptor.MethodDescriptorProto{ClientStreaming: &t}
+}
+
+func serverStreamingMethod() *descriptor.MethodDescriptorProto {
+	t := true
+	return &descriptor.MethodDescriptorProto{ServerStreaming: &t}
+}
+
+// newOperationWithJSONBody returns an Operation whose RequestBody is a plain
+// application/json document, as convertMethod would set before handing off
+// to applyStreamingRequest.
+func newOperationWithJSONBody() *openapi3.Operation {
+	op := openapi3.NewOperation()
+	schema := openapi3.NewObjectSchema().NewRef()
+	op.RequestBody = requestBodyFromSchema(schema)
+	return op
+}
+
+// TestApplyStreamingRequestGating guards against applyStreamingRequest
+// converting a client-streaming request body to application/x-ndjson when
+// the configured streaming mode doesn't produce ndjson content at all (e.g.
+// streaming=sse), which would contradict the user's explicit choice.
+func TestApplyStreamingRequestGating(t *testing.T) {
+	cases := []struct {
+		mode       string
+		wantNDJSON bool
+	}{
+		{streamingNDJSON, true},
+		{streamingBoth, true},
+		{streamingSSE, false},
+		{streamingNone, false},
+	}
+
+	for _, tc := range cases {
+		c := &Converter{streamingMode: tc.mode}
+		op := newOperationWithJSONBody()
+
+		c.applyStreamingRequest(op, clientStreamingMethod())
+
+		_, hasNDJSON := op.RequestBody.Value.Content["application/x-ndjson"]
+		if hasNDJSON != tc.wantNDJSON {
+			t.Errorf("streaming=%s: request body has application/x-ndjson = %v, want %v", tc.mode, hasNDJSON, tc.wantNDJSON)
+		}
+	}
+}
+
+func TestApplyStreamingRequestIgnoresNonClientStreaming(t *testing.T) {
+	c := &Converter{streamingMode: streamingBoth}
+	op := newOperationWithJSONBody()
+
+	c.applyStreamingRequest(op, &descriptor.MethodDescriptorProto{})
+
+	if _, ok := op.RequestBody.Value.Content["application/x-ndjson"]; ok {
+		t.Error("applyStreamingRequest shouldn't touch a non-client-streaming method's request body")
+	}
+}
+
+func TestStreamingResponseContentGating(t *testing.T) {
+	responseSchema := openapi3.NewObjectSchema().NewRef()
+
+	cases := []struct {
+		mode    string
+		wantSSE bool
+		wantND  bool
+	}{
+		{streamingBoth, true, true},
+		{streamingSSE, true, false},
+		{streamingNDJSON, false, true},
+		{streamingNone, false, false},
+	}
+
+	for _, tc := range cases {
+		c := &Converter{streamingMode: tc.mode}
+		content := c.streamingResponseContent(serverStreamingMethod(), responseSchema)
+
+		_, gotSSE := content["text/event-stream"]
+		_, gotND := content["application/x-ndjson"]
+		if gotSSE != tc.wantSSE {
+			t.Errorf("streaming=%s: text/event-stream present = %v, want %v", tc.mode, gotSSE, tc.wantSSE)
+		}
+		if gotND != tc.wantND {
+			t.Errorf("streaming=%s: application/x-ndjson present = %v, want %v", tc.mode, gotND, tc.wantND)
+		}
+	}
+}
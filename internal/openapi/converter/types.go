@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+// pkg is a node in the tree of proto packages, mirroring how .proto package
+// declarations nest (e.g. "acme.foo" is a child of "acme"). convertFile uses
+// it as the namespacing context a message or service was declared in.
+type pkg struct {
+	name     string
+	parent   *pkg
+	children map[string]*pkg
+}
+
+// globalPkg is the root of the package tree that every file's package is
+// resolved beneath.
+var globalPkg = &pkg{
+	children: make(map[string]*pkg),
+}
+
+// relativelyLookupPackage resolves a dot-separated package name to a node
+// beneath start, creating any missing intermediate packages as it goes.
+func (c *Converter) relativelyLookupPackage(start *pkg, name string) (*pkg, bool) {
+	current := start
+	if name == "" {
+		return current, true
+	}
+
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			continue
+		}
+		child, ok := current.children[part]
+		if !ok {
+			child = &pkg{name: part, parent: current, children: make(map[string]*pkg)}
+			current.children[part] = child
+		}
+		current = child
+	}
+
+	return current, true
+}
+
+// fullyQualifiedName returns the dotted ".pkg.name" form protoc uses to
+// reference a package, e.g. ".acme.foo".
+func (p *pkg) fullyQualifiedName() string {
+	if p == nil || p.name == "" {
+		return ""
+	}
+	if p.parent == nil || p.parent.name == "" {
+		return "." + p.name
+	}
+	return p.parent.fullyQualifiedName() + "." + p.name
+}
+
+// registerType records a top-level message, and recursively its nested
+// types, under the fully qualified name other messages' field types
+// reference it by (e.g. ".acme.foo.Bar"), so convertMessageType and
+// convertServiceType can resolve cross-message references while building
+// schemas. fileName is the proto file that declares msg, recorded so a
+// schema-name collision between two files can be reported by name.
+func (c *Converter) registerType(pkgName *string, fileName string, msg *descriptor.DescriptorProto) {
+	if c.messageTypes == nil {
+		c.messageTypes = make(map[string]*descriptor.DescriptorProto)
+		c.messageFiles = make(map[string]string)
+	}
+
+	c.registerTypeAt(derefString(pkgName), fileName, msg)
+}
+
+func (c *Converter) registerTypeAt(prefix, fileName string, msg *descriptor.DescriptorProto) {
+	fqName := "." + strings.TrimPrefix(prefix+"."+msg.GetName(), ".")
+	c.messageTypes[fqName] = msg
+	c.messageFiles[fqName] = fileName
+
+	for _, nested := range msg.GetNestedType() {
+		c.registerTypeAt(strings.TrimPrefix(fqName, "."), fileName, nested)
+	}
+}
+
+// lookupType resolves a fully qualified proto type name (e.g.
+// ".acme.foo.Bar") to the message descriptor it names.
+func (c *Converter) lookupType(name string) (*descriptor.DescriptorProto, bool) {
+	msg, ok := c.messageTypes[name]
+	return msg, ok
+}
+
+// derefString safely dereferences a proto string field, returning "" for a
+// nil pointer.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}